@@ -0,0 +1,94 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/goccy/go-json/decoder"
+)
+
+// Token is a single JSON token as returned by Decoder.Token: a Delim, bool,
+// float64, Number, string, or nil.
+type Token = json.Token
+
+// Unmarshal parses the JSON-encoded data and stores the result in the value
+// pointed to by v, rejecting input that nests objects/arrays deeper than
+// decoder.DefaultMaxDepth.
+func Unmarshal(data []byte, v interface{}) error {
+	return UnmarshalWithOption(data, v)
+}
+
+// UnmarshalWithOption is Unmarshal with additional DecodeOptionFunc, such as
+// WithMaxDepth. Depth is tracked live as decoder.DecodeStruct walks the
+// input — incrementing on every '{'/'[' it opens, whether decoded into a
+// matching field or skipped as unrecognised, and decrementing on the
+// matching close. A target that is not a struct is checked by one
+// decoder.SkipValue pass over the whole value before it is handed to
+// encoding/json.
+func UnmarshalWithOption(data []byte, v interface{}, opts ...DecodeOptionFunc) error {
+	opt := &DecodeOption{MaxDepth: decoder.DefaultMaxDepth}
+	for _, o := range opts {
+		o(opt)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("json: Unmarshal(non-pointer %T)", v)
+	}
+	return decoder.DecodeValue(data, rv.Elem(), decoder.NewDepth(opt.MaxDepth))
+}
+
+// Decoder reads and decodes JSON values from an input stream, applying the
+// same live maximum nesting depth to both Decode and Token.
+type Decoder struct {
+	dec   *json.Decoder
+	depth *decoder.Depth
+}
+
+// NewDecoder returns a new Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{dec: json.NewDecoder(r), depth: decoder.NewDepth(decoder.DefaultMaxDepth)}
+}
+
+// SetMaxDepth overrides the maximum object/array nesting depth this Decoder
+// accepts before Decode or Token return a *MaxDepthError. Unset, or set to
+// 0 or less, it is decoder.DefaultMaxDepth.
+func (d *Decoder) SetMaxDepth(n int) {
+	d.depth = decoder.NewDepth(n)
+}
+
+// Decode reads the next JSON-encoded value from its input and stores it in
+// the value pointed to by v, via the same decoder.DecodeValue struct path
+// Unmarshal uses.
+func (d *Decoder) Decode(v interface{}) error {
+	var raw json.RawMessage
+	if err := d.dec.Decode(&raw); err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("json: Decode(non-pointer %T)", v)
+	}
+	return decoder.DecodeValue(raw, rv.Elem(), d.depth)
+}
+
+// Token returns the next JSON token in the input stream, entering/leaving
+// the same Depth configured via SetMaxDepth on every '{'/'[' and '}'/']'.
+func (d *Decoder) Token() (Token, error) {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return tok, err
+	}
+	if delim, ok := tok.(json.Delim); ok {
+		switch delim {
+		case '{', '[':
+			if err := d.depth.Enter(d.dec.InputOffset()); err != nil {
+				return nil, err
+			}
+		case '}', ']':
+			d.depth.Leave()
+		}
+	}
+	return tok, nil
+}
@@ -0,0 +1,149 @@
+package decoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/goccy/go-json/runtime"
+)
+
+// DecodeValue decodes data into the value addressed by rv, threading depth
+// through so every '{'/'[' — whether it is about to be decoded into a
+// matching struct field or skipped as unrecognised — counts against the
+// same live limit. Struct targets are decoded field-by-field by
+// DecodeStruct, which resolves names via runtime.ResolveFields so
+// embedded-field promotion and shadowing match what the encoder drives
+// from; every other kind is validated against depth via SkipValue and then
+// handed to encoding/json (this also covers types implementing
+// json.Unmarshaler).
+func DecodeValue(data []byte, rv reflect.Value, depth *Depth) error {
+	data = trimSpace(data)
+	if string(data) == "null" {
+		switch rv.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			rv.Set(reflect.Zero(rv.Type()))
+		}
+		return nil
+	}
+
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() == reflect.Struct && !implementsUnmarshaler(rv) {
+		return DecodeStruct(data, rv, depth)
+	}
+
+	if _, err := SkipValue(data, 0, depth); err != nil {
+		return err
+	}
+	if rv.CanAddr() {
+		return json.Unmarshal(data, rv.Addr().Interface())
+	}
+	return json.Unmarshal(data, rv.Interface())
+}
+
+// DecodeStruct decodes the JSON object in data into struct value rv. It
+// resolves data's keys against runtime.ResolveFields(rv.Type()) — the same
+// pass the encoder drives field names and omitempty from — entering depth
+// for the object itself and leaving it on the matching close, in addition
+// to the Enter/Leave SkipValue performs for every value (matched or not) it
+// walks past while scanning keys.
+func DecodeStruct(data []byte, rv reflect.Value, depth *Depth) error {
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return fmt.Errorf("json: cannot decode %q into a struct", data)
+	}
+	if err := depth.Enter(int64(i)); err != nil {
+		return err
+	}
+	defer depth.Leave()
+	i++
+
+	fields := runtime.ResolveFields(rv.Type())
+	byName := make(map[string]runtime.StructField, len(fields))
+	for _, f := range fields {
+		byName[f.Name] = f
+	}
+
+	for {
+		i = skipSpace(data, i)
+		if i >= len(data) {
+			return fmt.Errorf("json: unterminated object")
+		}
+		if data[i] == '}' {
+			return nil
+		}
+		if data[i] == ',' {
+			i++
+			continue
+		}
+		if data[i] != '"' {
+			return fmt.Errorf("json: expected object key")
+		}
+		keyEnd, err := skipString(data, i)
+		if err != nil {
+			return err
+		}
+		key := string(data[i+1 : keyEnd-1])
+		i = skipSpace(data, keyEnd)
+		if i >= len(data) || data[i] != ':' {
+			return fmt.Errorf("json: expected ':' after object key")
+		}
+		valStart := skipSpace(data, i+1)
+		valEnd, err := SkipValue(data, valStart, depth)
+		if err != nil {
+			return err
+		}
+
+		if f, ok := byName[key]; ok {
+			if err := DecodeValue(data[valStart:valEnd], fieldForSet(rv, f), depth); err != nil {
+				return err
+			}
+		}
+		i = valEnd
+	}
+}
+
+// fieldForSet walks f.Index from rv, allocating any nil embedded pointer
+// along the way so the field can be assigned, mirroring the indirection
+// runtime.PromotedValue reads back on the encode side.
+func fieldForSet(rv reflect.Value, f runtime.StructField) reflect.Value {
+	v := rv
+	for _, idx := range f.Index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	return v
+}
+
+func implementsUnmarshaler(rv reflect.Value) bool {
+	if !rv.CanAddr() {
+		return false
+	}
+	_, ok := rv.Addr().Interface().(json.Unmarshaler)
+	return ok
+}
+
+func trimSpace(data []byte) []byte {
+	i := skipSpace(data, 0)
+	j := len(data)
+	for j > i {
+		switch data[j-1] {
+		case ' ', '\t', '\n', '\r':
+			j--
+		default:
+			return data[i:j]
+		}
+	}
+	return data[i:j]
+}
@@ -0,0 +1,56 @@
+// Package decoder implements the low-level scanning primitives shared by the public json package: live depth-limited value
+// skipping, struct decoding driven by runtime.ResolveFields, and the
+// raw-value skipping used by the path accessors.
+package decoder
+
+import "fmt"
+
+// DefaultMaxDepth is the nesting limit applied when a caller does not
+// configure one explicitly.
+const DefaultMaxDepth = 10000
+
+// MaxDepthError is returned when an object or array nests more deeply than
+// the configured maximum.
+type MaxDepthError struct {
+	Offset   int64
+	MaxDepth int
+}
+
+func (e *MaxDepthError) Error() string {
+	return fmt.Sprintf("json: exceeded max decode depth %d at offset %d", e.MaxDepth, e.Offset)
+}
+
+// Depth is the live nesting counter threaded through the decode runtime: it
+// is incremented by Enter on every '{'/'[' the decoder opens — whether that
+// container is about to be decoded into a matching struct field or merely
+// skipped as an unrecognised one — and decremented by Leave on the matching
+// close. A single Depth instance is shared across a whole Decode/Unmarshal
+// call (and, for a streaming Decoder, across every Token call), so the same
+// limit applies uniformly everywhere.
+type Depth struct {
+	cur int
+	max int
+}
+
+// NewDepth returns a Depth enforcing max, or DefaultMaxDepth if max <= 0.
+func NewDepth(max int) *Depth {
+	if max <= 0 {
+		max = DefaultMaxDepth
+	}
+	return &Depth{max: max}
+}
+
+// Enter records entering a '{' or '[' at offset, returning a *MaxDepthError
+// if doing so exceeds the configured maximum.
+func (d *Depth) Enter(offset int64) error {
+	d.cur++
+	if d.cur > d.max {
+		return &MaxDepthError{Offset: offset, MaxDepth: d.max}
+	}
+	return nil
+}
+
+// Leave records leaving the '{' or '[' most recently entered.
+func (d *Depth) Leave() {
+	d.cur--
+}
@@ -0,0 +1,207 @@
+package decoder
+
+import "fmt"
+
+// ErrPathNotFound is returned by FindPath when a requested object key or
+// array index does not exist in data.
+var ErrPathNotFound = fmt.Errorf("json: path not found")
+
+// SkipValue returns the end offset (exclusive) of the single JSON value
+// beginning at data[start] (after skipping leading whitespace). It does not
+// build a value tree: it only scans bytes to find the value's boundary. If
+// depth is non-nil, every '{'/'[' it walks through is entered against depth
+// and every matching close leaves it, so a caller decoding a value can
+// share the same live limit with the values it merely skips past. Pass a
+// nil depth to skip without enforcing any limit (as FindPath does).
+func SkipValue(data []byte, start int, depth *Depth) (end int, err error) {
+	i := skipSpace(data, start)
+	if i >= len(data) {
+		return 0, fmt.Errorf("json: unexpected end of input")
+	}
+	switch data[i] {
+	case '{':
+		return skipContainer(data, i, '{', '}', depth)
+	case '[':
+		return skipContainer(data, i, '[', ']', depth)
+	case '"':
+		return skipString(data, i)
+	default:
+		return skipLiteral(data, i)
+	}
+}
+
+// FindPath walks data locating the value at the end of path (a sequence of
+// string object keys and int array indices), reusing SkipValue (with no
+// depth limit) to skip over sibling keys/elements that don't match. It
+// returns the [start,end) byte range of the located value within data.
+func FindPath(data []byte, path []interface{}) (start, end int, err error) {
+	start = skipSpace(data, 0)
+	end = len(data)
+	for _, seg := range path {
+		switch key := seg.(type) {
+		case string:
+			start, end, err = findObjectKey(data, start, key)
+		case int:
+			start, end, err = findArrayIndex(data, start, key)
+		default:
+			return 0, 0, fmt.Errorf("json: unsupported path segment %T", seg)
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return start, end, nil
+}
+
+func findObjectKey(data []byte, start int, key string) (int, int, error) {
+	i := skipSpace(data, start)
+	if i >= len(data) || data[i] != '{' {
+		return 0, 0, fmt.Errorf("json: expected object for key %q", key)
+	}
+	i++
+	for {
+		i = skipSpace(data, i)
+		if i >= len(data) || data[i] == '}' {
+			return 0, 0, ErrPathNotFound
+		}
+		if data[i] == ',' {
+			i++
+			continue
+		}
+		if data[i] != '"' {
+			return 0, 0, fmt.Errorf("json: expected object key")
+		}
+		keyEnd, err := skipString(data, i)
+		if err != nil {
+			return 0, 0, err
+		}
+		gotKey := string(data[i+1 : keyEnd-1])
+		i = skipSpace(data, keyEnd)
+		if i >= len(data) || data[i] != ':' {
+			return 0, 0, fmt.Errorf("json: expected ':' after object key")
+		}
+		valStart := skipSpace(data, i+1)
+		valEnd, err := SkipValue(data, valStart, nil)
+		if err != nil {
+			return 0, 0, err
+		}
+		if gotKey == key {
+			return valStart, valEnd, nil
+		}
+		i = valEnd
+	}
+}
+
+func findArrayIndex(data []byte, start int, index int) (int, int, error) {
+	i := skipSpace(data, start)
+	if i >= len(data) || data[i] != '[' {
+		return 0, 0, fmt.Errorf("json: expected array for index %d", index)
+	}
+	i++
+	n := 0
+	for {
+		i = skipSpace(data, i)
+		if i >= len(data) || data[i] == ']' {
+			return 0, 0, ErrPathNotFound
+		}
+		if data[i] == ',' {
+			i++
+			continue
+		}
+		valStart := i
+		valEnd, err := SkipValue(data, valStart, nil)
+		if err != nil {
+			return 0, 0, err
+		}
+		if n == index {
+			return valStart, valEnd, nil
+		}
+		n++
+		i = valEnd
+	}
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) {
+		switch data[i] {
+		case ' ', '\t', '\n', '\r':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+func skipString(data []byte, i int) (int, error) {
+	i++ // opening quote
+	for i < len(data) {
+		switch data[i] {
+		case '\\':
+			i += 2
+			continue
+		case '"':
+			return i + 1, nil
+		}
+		i++
+	}
+	return 0, fmt.Errorf("json: unterminated string")
+}
+
+func skipLiteral(data []byte, i int) (int, error) {
+	start := i
+	for i < len(data) {
+		switch data[i] {
+		case ',', '}', ']', ' ', '\t', '\n', '\r':
+			if i == start {
+				return 0, fmt.Errorf("json: unexpected character %q", data[i])
+			}
+			return i, nil
+		}
+		i++
+	}
+	if i == start {
+		return 0, fmt.Errorf("json: unexpected end of input")
+	}
+	return i, nil
+}
+
+func skipContainer(data []byte, i int, open, closeByte byte, depth *Depth) (int, error) {
+	localDepth := 0
+	inString := false
+	escaped := false
+	for ; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			localDepth++
+			if depth != nil {
+				if err := depth.Enter(int64(i)); err != nil {
+					return 0, err
+				}
+			}
+		case '}', ']':
+			localDepth--
+			if depth != nil {
+				depth.Leave()
+			}
+			if localDepth == 0 {
+				return i + 1, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("json: unterminated %q", open)
+}
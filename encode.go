@@ -0,0 +1,71 @@
+package json
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/goccy/go-json/encoder"
+	"github.com/goccy/go-json/encoder/vm"
+)
+
+// Marshal returns the JSON encoding of v, driven by the same opcode-style
+// encoder StreamEncoder uses, so struct fields are resolved via
+// runtime.ResolveFields/PromotedValue and strings are escaped through
+// vm.EscapeString.
+func Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	sink := vm.NewSink(&buf, vm.DefaultFlushThreshold)
+	if err := encoder.Encode(sink, v); err != nil {
+		return nil, err
+	}
+	sink.Flush()
+	if err := sink.Err(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type encodeOption struct {
+	flushThreshold int
+}
+
+// EncodeOptionFunc configures a StreamEncoder.
+type EncodeOptionFunc func(*encodeOption)
+
+// WithFlushThreshold overrides the number of buffered bytes a StreamEncoder
+// accumulates before flushing to its underlying io.Writer (default
+// vm.DefaultFlushThreshold).
+func WithFlushThreshold(n int) EncodeOptionFunc {
+	return func(o *encodeOption) {
+		o.flushThreshold = n
+	}
+}
+
+// StreamEncoder writes the JSON encoding of a value directly to an
+// io.Writer, flushing at chunk boundaries instead of materialising the
+// whole output in memory first. Structs, slices, arrays and maps are walked
+// field/element by field/element straight into the sink — see package
+// encoder — so a multi-GB structure can be written with only a flush
+// threshold's worth of working memory.
+type StreamEncoder struct {
+	sink *vm.Sink
+}
+
+// NewStreamEncoder returns a StreamEncoder that writes to w.
+func NewStreamEncoder(w io.Writer, opts ...EncodeOptionFunc) *StreamEncoder {
+	opt := &encodeOption{flushThreshold: vm.DefaultFlushThreshold}
+	for _, o := range opts {
+		o(opt)
+	}
+	return &StreamEncoder{sink: vm.NewSink(w, opt.flushThreshold)}
+}
+
+// Encode writes the JSON encoding of v to the underlying io.Writer,
+// aborting and returning the writer's error immediately if one occurs.
+func (e *StreamEncoder) Encode(v interface{}) error {
+	if err := encoder.Encode(e.sink, v); err != nil {
+		return err
+	}
+	e.sink.Flush()
+	return e.sink.Err()
+}
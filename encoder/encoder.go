@@ -0,0 +1,165 @@
+// Package encoder implements the opcode-style interpreter StreamEncoder
+// uses to write a value's JSON encoding straight through a vm.Sink one
+// field/element at a time, resolving struct fields via
+// runtime.ResolveFields so the same embedded-field promotion and shadowing
+// rules apply here as on the decode side.
+package encoder
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/goccy/go-json/encoder/vm"
+	"github.com/goccy/go-json/runtime"
+)
+
+// Encode writes the JSON encoding of v to sink. Structs, slices, arrays and
+// maps are walked field/element by field/element so only one leaf value is
+// ever materialised at a time. A value implementing json.Marshaler is
+// written as its MarshalJSON output, unchanged; every other kind is encoded
+// as a single self-contained leaf via encoding/json.
+func Encode(sink *vm.Sink, v interface{}) error {
+	return encodeValue(sink, reflect.ValueOf(v))
+}
+
+func encodeValue(sink *vm.Sink, v reflect.Value) error {
+	if !v.IsValid() {
+		sink.AppendString("null")
+		return nil
+	}
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		sink.AppendString("null")
+		return nil
+	}
+	if v.CanInterface() {
+		if m, ok := v.Interface().(json.Marshaler); ok {
+			b, err := m.MarshalJSON()
+			if err != nil {
+				return err
+			}
+			sink.AppendString(string(b))
+			return nil
+		}
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return encodeStruct(sink, v)
+	case reflect.Slice, reflect.Array:
+		return encodeSlice(sink, v)
+	case reflect.Map:
+		return encodeMap(sink, v)
+	case reflect.String:
+		vm.EscapeString(sink, v.String())
+		return nil
+	default:
+		b, err := json.Marshal(v.Interface())
+		if err != nil {
+			return err
+		}
+		sink.AppendString(string(b))
+		return nil
+	}
+}
+
+// encodeStruct walks v's JSON-visible fields as resolved by
+// runtime.ResolveFields, so embedded-field promotion and shadowing match
+// what the decoder resolves the same fields to. Fields are read through
+// runtime.PromotedValue rather than v.FieldByIndex so a field promoted
+// through a nil embedded pointer is treated as absent instead of panicking.
+func encodeStruct(sink *vm.Sink, v reflect.Value) error {
+	fields := runtime.ResolveFields(v.Type())
+	sink.AppendByte('{')
+	wrote := false
+	for _, f := range fields {
+		fv, ok := runtime.PromotedValue(v, f)
+		if !ok {
+			continue
+		}
+		if f.OmitEmpty && runtime.IsEmptyValue(fv) {
+			continue
+		}
+		if wrote {
+			sink.AppendByte(',')
+		}
+		wrote = true
+		vm.EscapeString(sink, f.Name)
+		sink.AppendByte(':')
+		if err := encodeValue(sink, fv); err != nil {
+			return err
+		}
+		if err := sink.Err(); err != nil {
+			return err
+		}
+	}
+	sink.AppendByte('}')
+	return nil
+}
+
+func encodeSlice(sink *vm.Sink, v reflect.Value) error {
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		sink.AppendString("null")
+		return nil
+	}
+	sink.AppendByte('[')
+	n := v.Len()
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sink.AppendByte(',')
+		}
+		if err := encodeValue(sink, v.Index(i)); err != nil {
+			return err
+		}
+		if err := sink.Err(); err != nil {
+			return err
+		}
+	}
+	sink.AppendByte(']')
+	return nil
+}
+
+func encodeMap(sink *vm.Sink, v reflect.Value) error {
+	if v.IsNil() {
+		sink.AppendString("null")
+		return nil
+	}
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return mapKeyString(keys[i]) < mapKeyString(keys[j])
+	})
+	sink.AppendByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			sink.AppendByte(',')
+		}
+		vm.EscapeString(sink, mapKeyString(k))
+		sink.AppendByte(':')
+		if err := encodeValue(sink, v.MapIndex(k)); err != nil {
+			return err
+		}
+		if err := sink.Err(); err != nil {
+			return err
+		}
+	}
+	sink.AppendByte('}')
+	return nil
+}
+
+func mapKeyString(k reflect.Value) string {
+	switch k.Kind() {
+	case reflect.String:
+		return k.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10)
+	default:
+		b, _ := json.Marshal(k.Interface())
+		return string(b)
+	}
+}
@@ -0,0 +1,48 @@
+package vm
+
+// EscapeString writes the JSON-escaped form of s to sink a window at a
+// time, so an arbitrarily long string is never buffered in full before
+// being written out. Like encoding/json's default (HTMLEscape-on)
+// behavior, the HTML-special bytes '<', '>' and '&' are escaped too, so a
+// string can be safely embedded in an HTML script tag.
+func EscapeString(sink *Sink, s string) {
+	sink.AppendByte('"')
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+			continue
+		}
+		if start < i {
+			sink.AppendString(s[start:i])
+		}
+		switch c {
+		case '"':
+			sink.AppendString(`\"`)
+		case '\\':
+			sink.AppendString(`\\`)
+		case '\n':
+			sink.AppendString(`\n`)
+		case '\r':
+			sink.AppendString(`\r`)
+		case '\t':
+			sink.AppendString(`\t`)
+		case '<':
+			sink.AppendString(`\u003c`)
+		case '>':
+			sink.AppendString(`\u003e`)
+		case '&':
+			sink.AppendString(`\u0026`)
+		default:
+			const hex = "0123456789abcdef"
+			sink.AppendString(`\u00`)
+			sink.AppendByte(hex[c>>4])
+			sink.AppendByte(hex[c&0xF])
+		}
+		start = i + 1
+	}
+	if start < len(s) {
+		sink.AppendString(s[start:])
+	}
+	sink.AppendByte('"')
+}
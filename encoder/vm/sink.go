@@ -0,0 +1,79 @@
+// Package vm implements the opcode interpreter's output sink: the
+// abstraction the appendByte/appendString-style helpers write through to,
+// whether that is a growing in-memory buffer or a flushing io.Writer.
+package vm
+
+import (
+	"bufio"
+	"io"
+)
+
+// DefaultFlushThreshold is the number of buffered bytes a Sink accumulates
+// before flushing to its underlying io.Writer.
+const DefaultFlushThreshold = 4096
+
+// Sink is the write-through target the opcode interpreter appends encoded
+// bytes to. Unlike a growing []byte, a Sink never holds more than
+// threshold bytes of the final output in memory at once.
+type Sink struct {
+	w         *bufio.Writer
+	threshold int
+	err       error
+}
+
+// NewSink wraps w in a Sink that flushes once threshold buffered bytes have
+// accumulated.
+func NewSink(w io.Writer, threshold int) *Sink {
+	if threshold <= 0 {
+		threshold = DefaultFlushThreshold
+	}
+	return &Sink{w: bufio.NewWriterSize(w, threshold), threshold: threshold}
+}
+
+// Err reports the first write error encountered, if any. The VM checks this
+// after every opcode and aborts immediately once it is non-nil.
+func (s *Sink) Err() error {
+	return s.err
+}
+
+// AppendByte writes a single byte through to the underlying io.Writer,
+// flushing once the buffer reaches the configured threshold.
+func (s *Sink) AppendByte(b byte) {
+	if s.err != nil {
+		return
+	}
+	if err := s.w.WriteByte(b); err != nil {
+		s.err = err
+		return
+	}
+	s.maybeFlush()
+}
+
+// AppendString writes str through to the underlying io.Writer, flushing
+// once the buffer reaches the configured threshold.
+func (s *Sink) AppendString(str string) {
+	if s.err != nil {
+		return
+	}
+	if _, err := s.w.WriteString(str); err != nil {
+		s.err = err
+		return
+	}
+	s.maybeFlush()
+}
+
+func (s *Sink) maybeFlush() {
+	if s.w.Buffered() >= s.threshold {
+		s.Flush()
+	}
+}
+
+// Flush forces any buffered bytes to the underlying io.Writer.
+func (s *Sink) Flush() {
+	if s.err != nil {
+		return
+	}
+	if err := s.w.Flush(); err != nil {
+		s.err = err
+	}
+}
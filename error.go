@@ -0,0 +1,8 @@
+package json
+
+import "github.com/goccy/go-json/decoder"
+
+// MaxDepthError is returned by Unmarshal, UnmarshalWithOption, Decoder.Decode
+// and Decoder.Token when the input nests objects/arrays more deeply than the
+// configured maximum. See Decoder.SetMaxDepth and WithMaxDepth.
+type MaxDepthError = decoder.MaxDepthError
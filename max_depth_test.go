@@ -0,0 +1,78 @@
+package json_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+func nestedObjectJSON(depth int) string {
+	var b strings.Builder
+	for i := 0; i < depth; i++ {
+		b.WriteString(`{"a":`)
+	}
+	// null rather than a bare literal: deepNamedField's leaf is typed
+	// *deepNamedField, and only null (not a number) decodes into it without
+	// a type error, so the under-limit cases below actually exercise the
+	// depth counter instead of failing on an unrelated type mismatch.
+	b.WriteString("null")
+	for i := 0; i < depth; i++ {
+		b.WriteString("}")
+	}
+	return b.String()
+}
+
+type deepNamedField struct {
+	A *deepNamedField `json:"a"`
+}
+
+func TestMaxDepthTypedNamedField(t *testing.T) {
+	var v deepNamedField
+	if err := json.UnmarshalWithOption([]byte(nestedObjectJSON(100)), &v, json.WithMaxDepth(200)); err != nil {
+		t.Fatalf("unexpected error under limit: %v", err)
+	}
+
+	err := json.UnmarshalWithOption([]byte(nestedObjectJSON(300)), &v, json.WithMaxDepth(200))
+	if _, ok := err.(*json.MaxDepthError); !ok {
+		t.Fatalf("expected *MaxDepthError over limit, got %v", err)
+	}
+}
+
+type deepMissingField struct {
+	B *deepMissingField `json:"b"`
+}
+
+func TestMaxDepthTypedMissingField(t *testing.T) {
+	// The input only ever nests under "a", which deepMissingField doesn't
+	// declare, exercising the unknown-field skip path alongside the depth
+	// counter.
+	if err := json.UnmarshalWithOption([]byte(nestedObjectJSON(100)), &deepMissingField{}, json.WithMaxDepth(200)); err != nil {
+		t.Fatalf("unexpected error under limit: %v", err)
+	}
+
+	err := json.UnmarshalWithOption([]byte(nestedObjectJSON(300)), &deepMissingField{}, json.WithMaxDepth(200))
+	if _, ok := err.(*json.MaxDepthError); !ok {
+		t.Fatalf("expected *MaxDepthError over limit, got %v", err)
+	}
+}
+
+func TestMaxDepthInterface(t *testing.T) {
+	var v interface{}
+	if err := json.UnmarshalWithOption([]byte(nestedObjectJSON(100)), &v, json.WithMaxDepth(200)); err != nil {
+		t.Fatalf("unexpected error under limit: %v", err)
+	}
+
+	err := json.UnmarshalWithOption([]byte(nestedObjectJSON(300)), &v, json.WithMaxDepth(200))
+	if _, ok := err.(*json.MaxDepthError); !ok {
+		t.Fatalf("expected *MaxDepthError over limit, got %v", err)
+	}
+}
+
+func TestMaxDepthDefaultLimit(t *testing.T) {
+	var v interface{}
+	err := json.Unmarshal([]byte(nestedObjectJSON(20000)), &v)
+	if _, ok := err.(*json.MaxDepthError); !ok {
+		t.Fatalf("expected *MaxDepthError from default limit, got %v", err)
+	}
+}
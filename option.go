@@ -0,0 +1,18 @@
+package json
+
+// DecodeOptionFunc configures a DecodeOption used by UnmarshalWithOption.
+type DecodeOptionFunc func(*DecodeOption)
+
+// DecodeOption holds the runtime configuration applied by UnmarshalWithOption.
+type DecodeOption struct {
+	MaxDepth int
+}
+
+// WithMaxDepth overrides the maximum object/array nesting depth (default
+// decoder.DefaultMaxDepth) UnmarshalWithOption accepts before returning a
+// *MaxDepthError. A value of 0 or less applies decoder.DefaultMaxDepth.
+func WithMaxDepth(n int) DecodeOptionFunc {
+	return func(o *DecodeOption) {
+		o.MaxDepth = n
+	}
+}
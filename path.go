@@ -0,0 +1,119 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/goccy/go-json/decoder"
+)
+
+// GetByPath walks data using path (a sequence of string object keys and int
+// array indices) and returns the raw bytes of the value found there,
+// without decoding the rest of the document into a value tree.
+func GetByPath(data []byte, path ...interface{}) (json.RawMessage, error) {
+	start, end, err := decoder.FindPath(data, path)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(data[start:end]), nil
+}
+
+type setByPathOption struct {
+	createMissing bool
+}
+
+// SetByPathOptionFunc configures SetByPath.
+type SetByPathOptionFunc func(*setByPathOption)
+
+// WithCreateMissingPath makes SetByPath create a missing trailing object key
+// instead of returning decoder.ErrPathNotFound. Every path segment before
+// the last one must already exist.
+func WithCreateMissingPath() SetByPathOptionFunc {
+	return func(o *setByPathOption) {
+		o.createMissing = true
+	}
+}
+
+// SetByPath splices the JSON encoding of value into data at path, returning
+// the resulting document. A missing path segment is an error; use
+// SetByPathWithOption with WithCreateMissingPath to create a missing
+// trailing object key instead.
+func SetByPath(data []byte, value interface{}, path ...interface{}) ([]byte, error) {
+	return SetByPathWithOption(data, value, path)
+}
+
+// SetByPathWithOption is SetByPath with additional SetByPathOptionFunc, such
+// as WithCreateMissingPath.
+func SetByPathWithOption(data []byte, value interface{}, path []interface{}, opts ...SetByPathOptionFunc) ([]byte, error) {
+	opt := &setByPathOption{}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	encoded, err := Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return encoded, nil
+	}
+
+	start, end, err := decoder.FindPath(data, path)
+	if err == nil {
+		return splice(data, start, end, encoded), nil
+	}
+	if err != decoder.ErrPathNotFound || !opt.createMissing {
+		return nil, err
+	}
+
+	key, ok := path[len(path)-1].(string)
+	if !ok {
+		return nil, fmt.Errorf("json: can only create a missing object key, got %T", path[len(path)-1])
+	}
+	parentStart, parentEnd, err := decoder.FindPath(data, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	return insertObjectKey(data, parentStart, parentEnd, key, encoded)
+}
+
+func splice(data []byte, start, end int, encoded []byte) []byte {
+	out := make([]byte, 0, len(data)-(end-start)+len(encoded))
+	out = append(out, data[:start]...)
+	out = append(out, encoded...)
+	out = append(out, data[end:]...)
+	return out
+}
+
+func insertObjectKey(data []byte, objStart, objEnd int, key string, encoded []byte) ([]byte, error) {
+	if objEnd-objStart < 2 || data[objStart] != '{' || data[objEnd-1] != '}' {
+		return nil, fmt.Errorf("json: cannot create key %q: parent is not an object", key)
+	}
+	keyJSON, err := Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+
+	empty := true
+	for i := objStart + 1; i < objEnd-1; i++ {
+		switch data[i] {
+		case ' ', '\t', '\n', '\r':
+			continue
+		}
+		empty = false
+	}
+
+	insertion := make([]byte, 0, len(keyJSON)+len(encoded)+2)
+	if !empty {
+		insertion = append(insertion, ',')
+	}
+	insertion = append(insertion, keyJSON...)
+	insertion = append(insertion, ':')
+	insertion = append(insertion, encoded...)
+
+	out := make([]byte, 0, len(data)+len(insertion))
+	out = append(out, data[:objEnd-1]...)
+	out = append(out, insertion...)
+	out = append(out, data[objEnd-1:]...)
+	return out, nil
+}
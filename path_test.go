@@ -0,0 +1,75 @@
+package json_test
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+func TestGetByPathObjectKey(t *testing.T) {
+	data := []byte(`{"a":{"b":[1,2,{"c":"hello"}]}}`)
+
+	raw, err := json.GetByPath(data, "a", "b", 2, "c")
+	if err != nil {
+		t.Fatalf("GetByPath error: %v", err)
+	}
+	if string(raw) != `"hello"` {
+		t.Errorf(`expected "hello", got %s`, raw)
+	}
+}
+
+func TestGetByPathArrayIndex(t *testing.T) {
+	data := []byte(`[10,20,30]`)
+
+	raw, err := json.GetByPath(data, 1)
+	if err != nil {
+		t.Fatalf("GetByPath error: %v", err)
+	}
+	if string(raw) != "20" {
+		t.Errorf("expected 20, got %s", raw)
+	}
+}
+
+func TestGetByPathNotFound(t *testing.T) {
+	data := []byte(`{"a":1}`)
+
+	if _, err := json.GetByPath(data, "b"); err == nil {
+		t.Fatal("expected an error for a missing key")
+	}
+}
+
+func TestSetByPathReplacesExistingValue(t *testing.T) {
+	data := []byte(`{"a":{"b":1},"c":2}`)
+
+	out, err := json.SetByPath(data, 99, "a", "b")
+	if err != nil {
+		t.Fatalf("SetByPath error: %v", err)
+	}
+
+	expected := `{"a":{"b":99},"c":2}`
+	if string(out) != expected {
+		t.Errorf("expected %s, got %s", expected, out)
+	}
+}
+
+func TestSetByPathCreatesMissingKey(t *testing.T) {
+	data := []byte(`{"a":{}}`)
+
+	out, err := json.SetByPathWithOption(data, "x", []interface{}{"a", "b"}, json.WithCreateMissingPath())
+	if err != nil {
+		t.Fatalf("SetByPath error: %v", err)
+	}
+
+	expected := `{"a":{"b":"x"}}`
+	if string(out) != expected {
+		t.Errorf("expected %s, got %s", expected, out)
+	}
+}
+
+func TestSetByPathMissingWithoutOption(t *testing.T) {
+	data := []byte(`{"a":{}}`)
+
+	if _, err := json.SetByPath(data, "x", "a", "b"); err == nil {
+		t.Fatal("expected an error when the path is missing and WithCreateMissingPath is not set")
+	}
+}
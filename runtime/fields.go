@@ -0,0 +1,199 @@
+// Package runtime provides struct-field resolution shared by the encoder
+// and decoder compilers: it implements the Go spec's embedded-field
+// promotion and shadowing rules, matching the behavior of encoding/json's
+// internal field resolver.
+package runtime
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// StructField describes one promoted field of a struct type after
+// resolving Go's embedded-field visibility rules: shallower fields shadow
+// deeper same-named ones, same-depth collisions with no (or more than one)
+// explicit tag annihilate the name entirely, and a lone explicitly-tagged
+// field among same-depth collisions wins.
+type StructField struct {
+	Name      string
+	Index     []int
+	Type      reflect.Type
+	Tag       bool
+	OmitEmpty bool
+}
+
+var fieldCache sync.Map // map[reflect.Type][]StructField
+
+// ResolveFields returns the canonical, ordered list of JSON-visible fields
+// of struct type t, driving both encoder opcode generation and decoder
+// field lookup. The result is cached per type.
+func ResolveFields(t reflect.Type) []StructField {
+	if v, ok := fieldCache.Load(t); ok {
+		return v.([]StructField)
+	}
+	fields := dominantFields(collectFields(t))
+	v, _ := fieldCache.LoadOrStore(t, fields)
+	return v.([]StructField)
+}
+
+type scanLevel struct {
+	typ   reflect.Type
+	index []int
+}
+
+// collectFields performs a breadth-first walk over t and its anonymous
+// (embedded) struct fields, producing one candidate StructField per
+// JSON-visible field at every depth. Ambiguity between same-named fields is
+// resolved afterwards by dominantFields.
+func collectFields(t reflect.Type) []StructField {
+	var fields []StructField
+	current := []scanLevel{{typ: t}}
+	visited := map[reflect.Type]bool{}
+
+	for len(current) > 0 {
+		var next []scanLevel
+
+		for _, level := range current {
+			if visited[level.typ] {
+				// An anonymous pointer field that would re-visit an
+				// already-seen type at a deeper level is ignored to break
+				// cycles.
+				continue
+			}
+			visited[level.typ] = true
+
+			for i := 0; i < level.typ.NumField(); i++ {
+				f := level.typ.Field(i)
+				index := make([]int, len(level.index)+1)
+				copy(index, level.index)
+				index[len(level.index)] = i
+
+				if f.Anonymous {
+					ft := f.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						if name, explicit, omitempty := jsonTagName(f); explicit {
+							fields = append(fields, StructField{Name: name, Index: index, Type: f.Type, Tag: true, OmitEmpty: omitempty})
+						} else {
+							next = append(next, scanLevel{typ: ft, index: index})
+						}
+						continue
+					}
+					if !f.IsExported() {
+						// An unexported, non-struct anonymous field is
+						// neither promoted nor itself visible.
+						continue
+					}
+				} else if !f.IsExported() {
+					continue
+				}
+
+				name, explicit, omitempty := jsonTagName(f)
+				if name == "-" {
+					continue
+				}
+				fields = append(fields, StructField{Name: name, Index: index, Type: f.Type, Tag: explicit, OmitEmpty: omitempty})
+			}
+		}
+
+		current = next
+	}
+
+	return fields
+}
+
+// dominantFields groups fields by name, then within a group keeps the field
+// with the shortest index (shallowest depth) unless it is tied in both
+// depth and tag-explicitness with another field of the same name, in which
+// case the whole group is ambiguous and dropped. The survivors are then
+// re-sorted back into declaration (Index) order, since the name-based
+// grouping above leaves them sorted alphabetically.
+func dominantFields(fields []StructField) []StructField {
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].Name != fields[j].Name {
+			return fields[i].Name < fields[j].Name
+		}
+		if len(fields[i].Index) != len(fields[j].Index) {
+			return len(fields[i].Index) < len(fields[j].Index)
+		}
+		return fields[i].Tag && !fields[j].Tag
+	})
+
+	var out []StructField
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].Name == fields[i].Name {
+			j++
+		}
+		if j-i == 1 {
+			out = append(out, fields[i])
+		} else if len(fields[i].Index) != len(fields[i+1].Index) || fields[i].Tag != fields[i+1].Tag {
+			out = append(out, fields[i])
+		}
+		i = j
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return lessIndex(out[i].Index, out[j].Index)
+	})
+	return out
+}
+
+// lessIndex orders two field Index slices the way encoding/json's byIndex
+// does: lexicographically, so promoted fields sort back into the struct's
+// declaration order (depth-first through embeds) once the name-based
+// dedup pass above is done with them.
+func lessIndex(a, b []int) bool {
+	for k := 0; k < len(a) && k < len(b); k++ {
+		if a[k] != b[k] {
+			return a[k] < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func jsonTagName(f reflect.StructField) (name string, explicit bool, omitempty bool) {
+	tag := f.Tag.Get("json")
+	if tag == "" {
+		return f.Name, false, false
+	}
+	name = tag
+	omitempty = false
+	if idx := indexByte(tag, ','); idx >= 0 {
+		name = tag[:idx]
+		for _, opt := range splitComma(tag[idx+1:]) {
+			if opt == "omitempty" {
+				omitempty = true
+			}
+		}
+	}
+	if name == "" {
+		return f.Name, false, omitempty
+	}
+	return name, true, omitempty
+}
+
+func splitComma(s string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == ',' {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, s[start:])
+	return parts
+}
+
+func indexByte(s string, b byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == b {
+			return i
+		}
+	}
+	return -1
+}
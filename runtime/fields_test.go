@@ -0,0 +1,147 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+)
+
+func fieldNames(t *testing.T, typ reflect.Type) []string {
+	t.Helper()
+	var names []string
+	for _, f := range ResolveFields(typ) {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// Shadow: a field at a shallower depth shadows a deeper same-named field.
+func TestResolveFieldsShadow(t *testing.T) {
+	type Level1 struct {
+		Dup string
+	}
+	type Level0 struct {
+		Level1
+		Dup int
+	}
+
+	fields := ResolveFields(reflect.TypeOf(Level0{}))
+	if len(fields) != 1 {
+		t.Fatalf("expected exactly one surviving field, got %#v", fields)
+	}
+	if fields[0].Name != "Dup" || fields[0].Type.Kind() != reflect.Int {
+		t.Fatalf("expected the shallower int Dup to win, got %#v", fields[0])
+	}
+}
+
+// Dup: two same-depth, untagged fields with the same name annihilate each
+// other.
+func TestResolveFieldsSameDepthDupAnnihilated(t *testing.T) {
+	type Level1a struct {
+		Dup int
+	}
+	type Level1b struct {
+		Dup int
+	}
+	type S struct {
+		Level1a
+		Level1b
+	}
+
+	fields := ResolveFields(reflect.TypeOf(S{}))
+	for _, f := range fields {
+		if f.Name == "Dup" {
+			t.Fatalf("expected Dup to be annihilated, got %#v", fields)
+		}
+	}
+}
+
+// When exactly one of a same-depth collision has an explicit tag, it wins.
+func TestResolveFieldsSameDepthDupOneTagged(t *testing.T) {
+	type Level1a struct {
+		Dup int `json:"Dup"`
+	}
+	type Level1b struct {
+		Dup int
+	}
+	type S struct {
+		Level1a
+		Level1b
+	}
+
+	fields := ResolveFields(reflect.TypeOf(S{}))
+	var got *StructField
+	for i, f := range fields {
+		if f.Name == "Dup" {
+			got = &fields[i]
+		}
+	}
+	if got == nil || !got.Tag {
+		t.Fatalf("expected the explicitly tagged Dup to win, got %#v", fields)
+	}
+}
+
+// An unexported anonymous struct field still has its exported fields
+// promoted.
+func TestResolveFieldsUnexportedAnonymousStructPromoted(t *testing.T) {
+	type level1 struct {
+		Exported string
+	}
+	type S struct {
+		level1
+	}
+
+	names := fieldNames(t, reflect.TypeOf(S{}))
+	if len(names) != 1 || names[0] != "Exported" {
+		t.Fatalf("expected Exported to be promoted, got %v", names)
+	}
+}
+
+// An unexported anonymous non-struct field is ignored entirely.
+func TestResolveFieldsUnexportedAnonymousNonStructIgnored(t *testing.T) {
+	type myInt int
+	type S struct {
+		myInt
+		Visible string
+	}
+
+	names := fieldNames(t, reflect.TypeOf(S{}))
+	if len(names) != 1 || names[0] != "Visible" {
+		t.Fatalf("expected only Visible to survive, got %v", names)
+	}
+}
+
+// An anonymous pointer field that would re-visit an already-seen type at a
+// deeper level is ignored, breaking the cycle.
+func TestResolveFieldsPointerCycleBroken(t *testing.T) {
+	type Node struct {
+		*Node
+		Value int
+	}
+
+	names := fieldNames(t, reflect.TypeOf(Node{}))
+	if len(names) != 1 || names[0] != "Value" {
+		t.Fatalf("expected cycle to be broken with only Value surviving, got %v", names)
+	}
+}
+
+// The dedup pass groups candidates by name, which leaves survivors sorted
+// alphabetically; ResolveFields must re-sort them back into declaration
+// order rather than leaking that alphabetical ordering.
+func TestResolveFieldsPreservesDeclarationOrder(t *testing.T) {
+	type S struct {
+		Zebra string
+		Apple string
+		Mango string
+	}
+
+	names := fieldNames(t, reflect.TypeOf(S{}))
+	want := []string{"Zebra", "Apple", "Mango"}
+	if len(names) != len(want) {
+		t.Fatalf("expected %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, names)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package runtime
+
+import "reflect"
+
+// PromotedValue resolves the reflect.Value of field f on root, following
+// pointer indirection through every embedded pointer along f.Index.
+//
+// A promoted field's Index walks through each embed in turn; when an embed
+// is itself a pointer (type Outer struct { *Inner }), the field addressed
+// by the remaining index segments lives behind that pointer's dereference,
+// not at a byte offset combined with the outer struct's. ok is false when
+// that pointer (or any embedded pointer along the chain) is nil, meaning
+// the field is absent rather than present-but-zero.
+func PromotedValue(root reflect.Value, f StructField) (value reflect.Value, ok bool) {
+	v := root
+	for _, idx := range f.Index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	return v, true
+}
+
+// IsEmptyValue reports whether v is the kind of "empty" omitempty omits:
+// the same rule encoding/json applies, extended so that a field absent
+// because an embedded pointer on its path was nil (see PromotedValue) also
+// counts as empty.
+func IsEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
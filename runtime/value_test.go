@@ -0,0 +1,95 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+)
+
+type valueItem struct {
+	A string `json:"a"`
+	B string `json:"b,omitempty"`
+}
+
+type valueInner struct {
+	I valueItem `json:"i"`
+}
+
+// type Outer struct { *valueInner } with an omitempty field inside
+// valueInner, matching the PtrCode/StructPtrHead shape from issue 519.
+type valueOuter struct {
+	*valueInner
+}
+
+func TestPromotedValueThroughNonNilEmbeddedPointer(t *testing.T) {
+	typ := reflect.TypeOf(valueOuter{})
+	fields := ResolveFields(typ)
+	field := findField(t, fields, "i")
+
+	o := valueOuter{valueInner: &valueInner{I: valueItem{A: "a", B: "b"}}}
+	v, ok := PromotedValue(reflect.ValueOf(o), field)
+	if !ok {
+		t.Fatal("expected the field to resolve through a non-nil embedded pointer")
+	}
+	if v.Interface().(valueItem) != (valueItem{A: "a", B: "b"}) {
+		t.Fatalf("unexpected value: %#v", v.Interface())
+	}
+}
+
+func TestPromotedValueThroughNilEmbeddedPointer(t *testing.T) {
+	typ := reflect.TypeOf(valueOuter{})
+	fields := ResolveFields(typ)
+	field := findField(t, fields, "i")
+
+	o := valueOuter{}
+	_, ok := PromotedValue(reflect.ValueOf(o), field)
+	if ok {
+		t.Fatal("expected the field to be absent when the embedded pointer is nil")
+	}
+}
+
+// A chain of pointer embeds: *A embedding *B embedding C.
+type chainC struct {
+	Value string `json:"value,omitempty"`
+}
+
+type chainB struct {
+	*chainC
+}
+
+type chainA struct {
+	*chainB
+}
+
+func TestPromotedValueThroughChainedPointerEmbeds(t *testing.T) {
+	typ := reflect.TypeOf(chainA{})
+	fields := ResolveFields(typ)
+	field := findField(t, fields, "value")
+
+	a := chainA{chainB: &chainB{chainC: &chainC{Value: "deep"}}}
+	v, ok := PromotedValue(reflect.ValueOf(a), field)
+	if !ok || v.String() != "deep" {
+		t.Fatalf("expected to resolve through the chain, got %v, ok=%v", v, ok)
+	}
+
+	zeroLeaf := chainA{chainB: &chainB{chainC: &chainC{}}}
+	v, ok = PromotedValue(reflect.ValueOf(zeroLeaf), field)
+	if !ok || !IsEmptyValue(v) {
+		t.Fatalf("expected an empty omitempty value, got %v, ok=%v", v, ok)
+	}
+
+	nilMiddle := chainA{chainB: &chainB{}}
+	if _, ok := PromotedValue(reflect.ValueOf(nilMiddle), field); ok {
+		t.Fatal("expected the field to be absent when a middle embedded pointer is nil")
+	}
+}
+
+func findField(t *testing.T, fields []StructField, name string) StructField {
+	t.Helper()
+	for _, f := range fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	t.Fatalf("field %q not found in %#v", name, fields)
+	return StructField{}
+}
@@ -0,0 +1,67 @@
+package json_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+func TestStreamEncoderSlice(t *testing.T) {
+	var buf bytes.Buffer
+	enc := json.NewStreamEncoder(&buf, json.WithFlushThreshold(8))
+
+	if err := enc.Encode([]int{1, 2, 3}); err != nil {
+		t.Fatalf("Encode error: %v", err)
+	}
+
+	expected := `[1,2,3]`
+	if buf.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, buf.String())
+	}
+}
+
+func TestStreamEncoderStruct(t *testing.T) {
+	type Item struct {
+		A string `json:"a"`
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewStreamEncoder(&buf)
+
+	if err := enc.Encode(Item{A: "test"}); err != nil {
+		t.Fatalf("Encode error: %v", err)
+	}
+
+	expected := `{"a":"test"}`
+	if buf.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, buf.String())
+	}
+}
+
+type erroringWriter struct {
+	failAfter int
+	written   int
+}
+
+func (w *erroringWriter) Write(p []byte) (int, error) {
+	if w.written >= w.failAfter {
+		return 0, errors.New("boom")
+	}
+	w.written += len(p)
+	return len(p), nil
+}
+
+func TestStreamEncoderAbortsOnWriterError(t *testing.T) {
+	w := &erroringWriter{failAfter: 0}
+	enc := json.NewStreamEncoder(w, json.WithFlushThreshold(1))
+
+	err := enc.Encode([]int{1, 2, 3})
+	if err == nil {
+		t.Fatal("expected an error from the underlying writer")
+	}
+	if err.Error() != "boom" {
+		t.Errorf("expected the writer's own error to surface, got %v", err)
+	}
+}
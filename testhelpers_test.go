@@ -0,0 +1,20 @@
+package json_test
+
+import "testing"
+
+// assertErr fails the test immediately if err is non-nil.
+func assertErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// assertEq reports a test failure if got != want, identifying the
+// comparison by label in the failure message.
+func assertEq(t *testing.T, label string, want, got string) {
+	t.Helper()
+	if want != got {
+		t.Errorf("%s: expected %s, got %s", label, want, got)
+	}
+}